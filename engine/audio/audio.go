@@ -0,0 +1,24 @@
+// Package audio provides a PCM mixer for background music, sound effects,
+// and TTS, the Streamer interface that audio sinks implement, and an MP3
+// encoder for local captures of the mixed output. It contains no sink of its
+// own: the mixed PCM reaches a live stream only through a sink whose
+// StreamAudio muxes it, and streamer.FileFrameStreamer's discards it.
+package audio
+
+const (
+	// SampleRate is the PCM sample rate every Streamer and the Mixer expect,
+	// and the input rate PcmToMp3 encodes at.
+	SampleRate = 48000
+	// Channels is the PCM channel count (stereo) every Streamer and the
+	// Mixer expect.
+	Channels = 2
+)
+
+// Streamer is the audio equivalent of streamer.FrameStreamer: game code
+// pushes mixed, interleaved stereo int16 PCM into it and it is responsible
+// for getting those samples to wherever they need to go next (an FFmpeg
+// stdin pipe, a capture buffer, ...).
+type Streamer interface {
+	StreamAudio(samples []int16) error
+	Close() error
+}
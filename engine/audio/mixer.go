@@ -0,0 +1,98 @@
+package audio
+
+import "sync"
+
+// source is a single mixer input: background music, an SFX one-shot, a TTS
+// line... Samples pushed via Write queue up until the next Mix call
+// consumes them.
+type source struct {
+	volume float64
+	buf    []int16
+}
+
+// Mixer sums multiple concurrent PCM sources into a single stereo stream,
+// clamping the result so a loud combination of sources can't wrap around
+// instead of clipping.
+type Mixer struct {
+	mu      sync.Mutex
+	sources map[string]*source
+}
+
+// NewMixer creates an empty Mixer.
+func NewMixer() *Mixer {
+	return &Mixer{sources: make(map[string]*source)}
+}
+
+// AddSource registers (or resets) a named source at the given volume (0..1).
+func (m *Mixer) AddSource(id string, volume float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sources[id] = &source{volume: volume}
+}
+
+// RemoveSource drops a source from the mix.
+func (m *Mixer) RemoveSource(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sources, id)
+}
+
+// SetVolume adjusts a registered source's volume; call it repeatedly from a
+// timer to approximate a fade in/out.
+func (m *Mixer) SetVolume(id string, volume float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s, ok := m.sources[id]; ok {
+		s.volume = volume
+	}
+}
+
+// Write appends samples to a source's pending buffer.
+func (m *Mixer) Write(id string, samples []int16) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s, ok := m.sources[id]; ok {
+		s.buf = append(s.buf, samples...)
+	}
+}
+
+// Mix pulls n samples worth of audio out of every source (silence-padding
+// any source that's starved) and sums them into a single buffer, clipping
+// to the int16 range instead of wrapping around.
+func (m *Mixer) Mix(n int) []int16 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sums := make([]int32, n)
+	for _, s := range m.sources {
+		limit := len(s.buf)
+		if limit > n {
+			limit = n
+		}
+		for i := 0; i < limit; i++ {
+			sums[i] += int32(float64(s.buf[i]) * s.volume)
+		}
+		if n >= len(s.buf) {
+			s.buf = s.buf[:0]
+		} else {
+			s.buf = s.buf[n:]
+		}
+	}
+
+	out := make([]int16, n)
+	for i, v := range sums {
+		out[i] = clipInt16(v)
+	}
+	return out
+}
+
+func clipInt16(v int32) int16 {
+	switch {
+	case v > 32767:
+		return 32767
+	case v < -32768:
+		return -32768
+	default:
+		return int16(v)
+	}
+}
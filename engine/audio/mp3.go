@@ -0,0 +1,29 @@
+package audio
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/viert/lame"
+)
+
+// PcmToMp3 encodes a buffer of interleaved stereo int16 PCM samples (at
+// SampleRate) to MP3, so the `capture` command can dump synchronized
+// video+audio instead of silent frames.
+func PcmToMp3(pcm *bytes.Buffer) (*bytes.Buffer, error) {
+	enc := lame.Init()
+	defer enc.Close()
+
+	enc.SetNumChannels(Channels)
+	enc.SetInSamplerate(SampleRate)
+	enc.SetQuality(5)
+
+	if ret := enc.InitParams(); ret < 0 {
+		return nil, fmt.Errorf("lame: InitParams failed with code %d", ret)
+	}
+
+	out := bytes.NewBuffer(enc.Encode(pcm.Bytes()))
+	out.Write(enc.Flush())
+
+	return out, nil
+}
@@ -0,0 +1,188 @@
+// Package broadcast fans a single rendered frame out to zero or more
+// independently-managed streamer.FrameStreamer sinks, so the game engine can
+// be re-targeted to different destinations (YouTube, Twitch, a local file,
+// an HTTP-FLV preview, ...) without restarting.
+package broadcast
+
+import (
+	"YouTubeLiveGame/engine/audio"
+	"YouTubeLiveGame/engine/streamer"
+	"fmt"
+	"image"
+	"sync"
+)
+
+// SinkError pairs a sink id with the error its Stream call returned, so a
+// single fan-out failure doesn't need to be a generic joined error.
+type SinkError struct {
+	ID  string
+	Err error
+}
+
+func (e *SinkError) Error() string {
+	return fmt.Sprintf("sink %q: %v", e.ID, e.Err)
+}
+
+// Sink is a single managed destination inside a Manager.
+type Sink struct {
+	ID     string
+	URL    string
+	Stream streamer.FrameStreamer
+}
+
+// Manager holds the set of active sinks and fans out every frame to all of
+// them. A broken sink only affects itself: Stream keeps going to the
+// remaining sinks and reports the failing ones back to the caller.
+type Manager struct {
+	outputDir     string
+	width, height int
+
+	mu    sync.RWMutex
+	sinks map[string]*Sink
+}
+
+// NewManager creates an empty Manager. outputDir/width/height are passed
+// through to every sink created via Start, matching the parameters
+// NewFileFrameStreamer used to take directly.
+func NewManager(outputDir string, width, height int) *Manager {
+	return &Manager{
+		outputDir: outputDir,
+		width:     width,
+		height:    height,
+		sinks:     make(map[string]*Sink),
+	}
+}
+
+// Start spins up a new sink streaming to url and registers it under id. It
+// replaces any existing sink with the same id.
+func (m *Manager) Start(id, url string) error {
+	fileFrameStreamer, err := streamer.NewFileFrameStreamer(m.outputDir, url, m.width, m.height)
+	if err != nil {
+		return fmt.Errorf("failed to start sink %q: %w", id, err)
+	}
+
+	m.mu.Lock()
+	if existing, ok := m.sinks[id]; ok {
+		_ = existing.Stream.Close()
+	}
+	m.sinks[id] = &Sink{ID: id, URL: url, Stream: fileFrameStreamer}
+	m.mu.Unlock()
+
+	return nil
+}
+
+// Register adds an already-constructed sink under id, replacing any
+// existing sink with the same id. This is how non-URL sinks (e.g. the
+// telnet TextFrameStreamer) join the broadcast without going through Start.
+func (m *Manager) Register(id string, sink streamer.FrameStreamer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if existing, ok := m.sinks[id]; ok {
+		_ = existing.Stream.Close()
+	}
+	m.sinks[id] = &Sink{ID: id, Stream: sink}
+}
+
+// Stop closes and removes the sink registered under id.
+func (m *Manager) Stop(id string) error {
+	m.mu.Lock()
+	sink, ok := m.sinks[id]
+	if ok {
+		delete(m.sinks, id)
+	}
+	m.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("no sink registered with id %q", id)
+	}
+	return sink.Stream.Close()
+}
+
+// Get returns the sink registered under id and whether it exists, so
+// callers can type-assert it against a capability interface (e.g. HTTP-FLV
+// subscription) that not every FrameStreamer implements.
+func (m *Manager) Get(id string) (streamer.FrameStreamer, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sink, ok := m.sinks[id]
+	if !ok {
+		return nil, false
+	}
+	return sink.Stream, true
+}
+
+// List returns the ids and urls of every active sink.
+func (m *Manager) List() []Sink {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	list := make([]Sink, 0, len(m.sinks))
+	for _, sink := range m.sinks {
+		list = append(list, Sink{ID: sink.ID, URL: sink.URL})
+	}
+	return list
+}
+
+// Stream fans frame out to every active sink. A sink whose Stream call fails
+// is left registered (it manages its own reconnect, as FileFrameStreamer
+// does) but its id and error are collected so the caller can log them; one
+// broken sink never blocks or drops frames for the others.
+func (m *Manager) Stream(frame image.Image) []SinkError {
+	m.mu.RLock()
+	sinks := make([]*Sink, 0, len(m.sinks))
+	for _, sink := range m.sinks {
+		sinks = append(sinks, sink)
+	}
+	m.mu.RUnlock()
+
+	var errs []SinkError
+	for _, sink := range sinks {
+		if err := sink.Stream.Stream(frame); err != nil {
+			errs = append(errs, SinkError{ID: sink.ID, Err: err})
+		}
+	}
+	return errs
+}
+
+// StreamAudio fans mixed PCM samples out to every active sink that also
+// implements audio.Streamer (FileFrameStreamer does, and its StreamAudio
+// discards the samples; a sink without it, e.g. TextFrameStreamer, is
+// skipped).
+func (m *Manager) StreamAudio(samples []int16) []SinkError {
+	m.mu.RLock()
+	sinks := make([]*Sink, 0, len(m.sinks))
+	for _, sink := range m.sinks {
+		sinks = append(sinks, sink)
+	}
+	m.mu.RUnlock()
+
+	var errs []SinkError
+	for _, sink := range sinks {
+		audioSink, ok := sink.Stream.(audio.Streamer)
+		if !ok {
+			continue
+		}
+		if err := audioSink.StreamAudio(samples); err != nil {
+			errs = append(errs, SinkError{ID: sink.ID, Err: err})
+		}
+	}
+	return errs
+}
+
+// Close stops every active sink.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	sinks := m.sinks
+	m.sinks = make(map[string]*Sink)
+	m.mu.Unlock()
+
+	var firstErr error
+	for _, sink := range sinks {
+		if err := sink.Stream.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,150 @@
+package chat
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Handler is invoked when a chat message matches a registered pattern.
+type Handler func(msg Message)
+
+type binding struct {
+	prefix    bool
+	pattern   string
+	handler   Handler
+	cooldown  time.Duration
+	lastFired time.Time
+}
+
+// Router maps chat message patterns to game command handlers. It rate-limits
+// how often a single user's messages are accepted and lets each binding
+// declare its own cooldown, so a spammy chat can't flood the game loop.
+type Router struct {
+	mu       sync.Mutex
+	bindings []*binding
+
+	userMinInterval time.Duration
+	lastSeen        map[string]time.Time
+
+	voteMu      sync.Mutex
+	votes       map[string]int
+}
+
+// NewRouter creates a Router that accepts at most one message per user
+// every userMinInterval; pass 0 to disable per-user rate limiting.
+func NewRouter(userMinInterval time.Duration) *Router {
+	return &Router{
+		userMinInterval: userMinInterval,
+		lastSeen:        make(map[string]time.Time),
+		votes:           make(map[string]int),
+	}
+}
+
+// On registers handler for messages whose text equals pattern exactly.
+func (r *Router) On(pattern string, handler Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bindings = append(r.bindings, &binding{pattern: pattern, handler: handler})
+}
+
+// OnPrefix registers handler for messages whose text starts with prefix.
+func (r *Router) OnPrefix(prefix string, handler Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bindings = append(r.bindings, &binding{prefix: true, pattern: prefix, handler: handler})
+}
+
+// Cooldown sets the minimum interval between two firings of the exact-match
+// binding registered for pattern.
+func (r *Router) Cooldown(pattern string, cooldown time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, b := range r.bindings {
+		if !b.prefix && b.pattern == pattern {
+			b.cooldown = cooldown
+		}
+	}
+}
+
+// Route dispatches msg to every matching handler, enforcing the per-user
+// rate limit first and then each binding's own cooldown.
+func (r *Router) Route(msg Message) {
+	r.mu.Lock()
+	if r.userMinInterval > 0 {
+		if last, ok := r.lastSeen[msg.UserID]; ok && time.Since(last) < r.userMinInterval {
+			r.mu.Unlock()
+			return
+		}
+		r.lastSeen[msg.UserID] = time.Now()
+	}
+
+	now := time.Now()
+	var fire []*binding
+	for _, b := range r.bindings {
+		if b.prefix {
+			if !strings.HasPrefix(msg.Text, b.pattern) {
+				continue
+			}
+		} else if msg.Text != b.pattern {
+			continue
+		}
+		if b.cooldown > 0 && now.Sub(b.lastFired) < b.cooldown {
+			continue
+		}
+		b.lastFired = now
+		fire = append(fire, b)
+	}
+	r.mu.Unlock()
+
+	for _, b := range fire {
+		b.handler(msg)
+	}
+}
+
+// Run reads from source until its Messages channel closes, routing every
+// message it sees.
+func (r *Router) Run(source Source) {
+	for msg := range source.Messages() {
+		r.Route(msg)
+	}
+}
+
+// StartVote begins a rolling vote-aggregation window over the given
+// candidate commands (a Twitch-plays-style input mode): every message
+// matching one of them increments its tally, and at the end of every window
+// the candidate with the most votes is passed to onWinner. Ties are broken
+// by whichever candidate is listed first. A window with no votes fires
+// nothing.
+func (r *Router) StartVote(window time.Duration, candidates []string, onWinner func(command string)) {
+	for _, candidate := range candidates {
+		candidate := candidate
+		r.On(candidate, func(msg Message) {
+			r.voteMu.Lock()
+			r.votes[candidate]++
+			r.voteMu.Unlock()
+		})
+	}
+
+	go func() {
+		ticker := time.NewTicker(window)
+		defer ticker.Stop()
+		for range ticker.C {
+			r.voteMu.Lock()
+			winner := ""
+			best := 0
+			for _, candidate := range candidates {
+				if count := r.votes[candidate]; count > best {
+					best = count
+					winner = candidate
+				}
+				delete(r.votes, candidate)
+			}
+			r.voteMu.Unlock()
+
+			if winner != "" {
+				onWinner(winner)
+			}
+		}
+	}()
+}
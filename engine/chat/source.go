@@ -0,0 +1,19 @@
+// Package chat decouples the game engine from any one chat backend. A
+// Source turns some external chat feed (YouTube Live, Twitch IRC, a plain
+// WebSocket, ...) into a channel of Messages, and a Router maps those
+// messages onto game commands.
+package chat
+
+// Message is a single chat message coming from any Source.
+type Message struct {
+	UserID   string
+	Username string
+	Text     string
+}
+
+// Source produces chat messages from some backend and can be closed when no
+// longer needed.
+type Source interface {
+	Messages() <-chan Message
+	Close() error
+}
@@ -0,0 +1,14 @@
+package chat
+
+import "errors"
+
+// ErrNotImplemented is returned by chat sources that are stubbed out
+// pending an actual backend integration.
+var ErrNotImplemented = errors.New("chat: not implemented")
+
+// NewTwitchSource will connect to a channel's Twitch IRC chat once
+// implemented. Twitch IRC needs an OAuth token and its own PING/PONG
+// keepalive handling, which isn't wired up yet.
+func NewTwitchSource(oauthToken, channel string) (Source, error) {
+	return nil, ErrNotImplemented
+}
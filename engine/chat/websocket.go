@@ -0,0 +1,8 @@
+package chat
+
+// NewWebSocketSource will connect to url and treat every incoming text
+// frame as a chat message once implemented, for chat backends
+// that only expose a plain WebSocket feed.
+func NewWebSocketSource(url string) (Source, error) {
+	return nil, ErrNotImplemented
+}
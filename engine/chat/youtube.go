@@ -0,0 +1,92 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/youtube/v3"
+)
+
+// YouTubeSource polls the YouTube Live Chat Messages API for a given live
+// chat id and emits each message on Messages(). This is the polling loop
+// that used to live directly in main.go.
+type YouTubeSource struct {
+	messages chan Message
+	cancel   context.CancelFunc
+}
+
+// NewYouTubeSource starts polling chatId using apiKey and returns a Source
+// streaming its messages. Polling stops when Close is called.
+func NewYouTubeSource(apiKey, chatId string) (*YouTubeSource, error) {
+	service, err := youtube.NewService(context.Background(), option.WithAPIKey(apiKey))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	source := &YouTubeSource{
+		messages: make(chan Message, 100),
+		cancel:   cancel,
+	}
+
+	go source.poll(ctx, service, chatId)
+
+	return source, nil
+}
+
+func (s *YouTubeSource) poll(ctx context.Context, service *youtube.Service, chatId string) {
+	defer close(s.messages)
+
+	errCount := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if errCount > 10 {
+			fmt.Println("too many errors!")
+			errCount = 0
+			<-time.After(time.Minute)
+		}
+
+		if err := service.LiveChatMessages.List(chatId, []string{"snippet", "authorDetails"}).Pages(ctx, func(response *youtube.LiveChatMessageListResponse) error {
+			for _, item := range response.Items {
+				msg := Message{
+					UserID:   item.AuthorDetails.ChannelId,
+					Username: item.AuthorDetails.DisplayName,
+					Text:     strings.ToLower(item.Snippet.TextMessageDetails.MessageText),
+				}
+				select {
+				case s.messages <- msg:
+				default:
+				}
+			}
+			<-time.After(time.Second * 3)
+			return nil
+		}); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			errCount++
+			fmt.Println("error happened ", err, " count ", errCount, " waiting for 10 seconds")
+			<-time.After(time.Second * 10)
+		}
+	}
+}
+
+// Messages returns the channel of incoming chat messages.
+func (s *YouTubeSource) Messages() <-chan Message {
+	return s.messages
+}
+
+// Close stops polling. The Messages channel is closed once the in-flight
+// poll returns.
+func (s *YouTubeSource) Close() error {
+	s.cancel()
+	return nil
+}
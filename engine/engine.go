@@ -0,0 +1,178 @@
+// Package engine hosts the fixed-timestep game loop and Scene interface
+// that main.go used to inline: a ticker, hardcoded drawing, and manual
+// stats. This is the substrate a real game gets built on instead of
+// modifying main.go directly.
+package engine
+
+import (
+	"YouTubeLiveGame/engine/broadcast"
+	"fmt"
+	"time"
+
+	"github.com/fogleman/gg"
+)
+
+const (
+	// SimulationHz is how often Scene.Update ticks, decoupled from how
+	// often frames are actually rendered and streamed.
+	SimulationHz = 60
+	// RenderHz is how often Scene.Render runs and frames are pushed to the
+	// broadcast manager, matching the FLV output's frame rate.
+	RenderHz = 30
+)
+
+// Engine runs a fixed-timestep simulation loop alongside a render/stream
+// loop, decoupling the two the classic "fix your timestep" way: Update
+// always advances in SimulationHz-sized steps via an accumulator, however
+// long a tick actually took to arrive.
+type Engine struct {
+	width, height int
+	scene         Scene
+	broadcast     *broadcast.Manager
+	metrics       *Metrics
+
+	inputs chan Input
+
+	// AudioSource, if set, is called once per render tick to pull mixed PCM
+	// samples to stream alongside that frame's video. Decoupling it this
+	// way means the Engine doesn't need to know anything about audio.Mixer.
+	AudioSource func() []int16
+}
+
+// NewEngine creates an Engine that renders scene at width x height and
+// streams frames through manager.
+func NewEngine(width, height int, scene Scene, manager *broadcast.Manager) *Engine {
+	return &Engine{
+		width:     width,
+		height:    height,
+		scene:     scene,
+		broadcast: manager,
+		metrics:   NewMetrics(),
+		inputs:    make(chan Input, 100),
+	}
+}
+
+// Metrics returns the running Metrics, for wiring up a /metrics handler.
+func (e *Engine) Metrics() *Metrics {
+	return e.metrics
+}
+
+// Enqueue queues input for the next Update call. It never blocks: if the
+// queue is full the input is dropped, the same backpressure behavior
+// main.go's original `input` channel had.
+func (e *Engine) Enqueue(input Input) {
+	select {
+	case e.inputs <- input:
+	default:
+	}
+}
+
+// Run drives the engine until done is closed. Simulation advances at
+// SimulationHz using an accumulator; rendering and streaming happen at
+// RenderHz. Render always runs so the scene stays current; if Render alone
+// overruns the render frame budget, that tick's Stream/StreamAudio calls are
+// skipped (simulation keeps advancing regardless), the overrun is logged,
+// and the tick is recorded as a "dropped frame" in Metrics — i.e. stream
+// skipped, not render skipped. If only Render+Stream together overrun it,
+// the frame has already been streamed; the overrun is logged and the tick
+// is still recorded as a dropped frame.
+func (e *Engine) Run(done <-chan struct{}) {
+	ctx := gg.NewContext(e.width, e.height)
+	simStep := time.Second / SimulationHz
+	renderStep := time.Second / RenderHz
+
+	simTicker := time.NewTicker(simStep)
+	defer simTicker.Stop()
+	renderTicker := time.NewTicker(renderStep)
+	defer renderTicker.Stop()
+
+	var accumulator time.Duration
+	lastSim := time.Now()
+
+	for {
+		select {
+		case <-done:
+			return
+
+		case <-simTicker.C:
+			now := time.Now()
+			accumulator += now.Sub(lastSim)
+			lastSim = now
+			for accumulator >= simStep {
+				e.scene.Update(simStep, e.drainInputs())
+				accumulator -= simStep
+			}
+
+		case <-renderTicker.C:
+			e.renderAndStream(ctx, renderStep)
+		}
+	}
+}
+
+// pausable is implemented by Scenes that support pausing the live stream
+// without stopping simulation or rendering, e.g. gameScene's "toggle"
+// command.
+type pausable interface {
+	Paused() bool
+}
+
+func (e *Engine) renderAndStream(ctx *gg.Context, renderStep time.Duration) {
+	started := time.Now()
+	e.scene.Render(ctx)
+
+	// Audio is always pulled from the source to keep the mixer's internal
+	// buffers drained, even if this tick's frame ends up skipped below;
+	// otherwise a skipped tick leaves stale samples to bleed into the next
+	// successful one.
+	var audio []int16
+	if e.AudioSource != nil {
+		audio = e.AudioSource()
+	}
+
+	if elapsed := time.Since(started); elapsed > renderStep {
+		fmt.Printf("frame budget overrun: render took %s (budget %s), skipping this frame's stream\n", elapsed, renderStep)
+		e.metrics.RecordFrame(elapsed)
+		e.metrics.RecordDrop()
+		return
+	}
+
+	if p, ok := e.scene.(pausable); ok && p.Paused() {
+		e.metrics.RecordFrame(time.Since(started))
+		return
+	}
+
+	if sinkErrs := e.broadcast.Stream(ctx.Image()); len(sinkErrs) > 0 {
+		for _, sinkErr := range sinkErrs {
+			fmt.Println("error happened", sinkErr)
+			e.metrics.RecordReconnect(sinkErr.ID)
+		}
+	}
+
+	if audio != nil {
+		if sinkErrs := e.broadcast.StreamAudio(audio); len(sinkErrs) > 0 {
+			for _, sinkErr := range sinkErrs {
+				fmt.Println("error happened", sinkErr)
+				e.metrics.RecordReconnect(sinkErr.ID)
+			}
+		}
+	}
+
+	elapsed := time.Since(started)
+	if elapsed > renderStep {
+		fmt.Printf("frame budget overrun: render+stream took %s (budget %s)\n", elapsed, renderStep)
+		e.metrics.RecordDrop()
+	}
+	e.metrics.RecordFrame(elapsed)
+}
+
+func (e *Engine) drainInputs() []Input {
+	var inputs []Input
+	for {
+		select {
+		case input := <-e.inputs:
+			inputs = append(inputs, input)
+		default:
+			return inputs
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxFrameSamples bounds how many recent frame times Metrics keeps around
+// for the p95/p99 calculation (10s of history at 30Hz).
+const maxFrameSamples = 300
+
+// Metrics tracks the running frame-timing and reconnect statistics the
+// engine exposes at /metrics. It's hand-rolled rather than pulling in the
+// Prometheus client library, but Render emits the same text exposition
+// format so it drops straight into a Prometheus scrape config.
+type Metrics struct {
+	mu sync.Mutex
+
+	frameTimes     []time.Duration
+	droppedFrames  int
+	sinkReconnects map[string]int
+}
+
+// NewMetrics creates an empty Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{sinkReconnects: make(map[string]int)}
+}
+
+// RecordFrame appends a completed frame's total Render+Stream duration.
+func (m *Metrics) RecordFrame(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.frameTimes = append(m.frameTimes, d)
+	if len(m.frameTimes) > maxFrameSamples {
+		m.frameTimes = m.frameTimes[len(m.frameTimes)-maxFrameSamples:]
+	}
+}
+
+// RecordDrop marks a frame that blew the frame budget: either its stream
+// was skipped because Render alone overran, or Render+Stream together did.
+func (m *Metrics) RecordDrop() {
+	m.mu.Lock()
+	m.droppedFrames++
+	m.mu.Unlock()
+}
+
+// RecordReconnect counts one failed Stream or StreamAudio call, as reported
+// by broadcast.Manager, against the sink registered under sinkID. Reconnect
+// attempts a sink makes internally (FileFrameStreamer's backoff loop) are
+// not reported here, and a sink that keeps failing is counted once per call.
+func (m *Metrics) RecordReconnect(sinkID string) {
+	m.mu.Lock()
+	m.sinkReconnects[sinkID]++
+	m.mu.Unlock()
+}
+
+// Render formats the current metrics in Prometheus text exposition format
+// for the /metrics endpoint.
+func (m *Metrics) Render() string {
+	m.mu.Lock()
+	times := append([]time.Duration(nil), m.frameTimes...)
+	dropped := m.droppedFrames
+	reconnects := make(map[string]int, len(m.sinkReconnects))
+	for id, count := range m.sinkReconnects {
+		reconnects[id] = count
+	}
+	m.mu.Unlock()
+
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+
+	var avg, p95, p99 time.Duration
+	if len(times) > 0 {
+		var sum time.Duration
+		for _, t := range times {
+			sum += t
+		}
+		avg = sum / time.Duration(len(times))
+		p95 = times[percentileIndex(len(times), 0.95)]
+		p99 = times[percentileIndex(len(times), 0.99)]
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "engine_frame_time_avg_seconds %f\n", avg.Seconds())
+	fmt.Fprintf(&sb, "engine_frame_time_p95_seconds %f\n", p95.Seconds())
+	fmt.Fprintf(&sb, "engine_frame_time_p99_seconds %f\n", p99.Seconds())
+	fmt.Fprintf(&sb, "engine_dropped_frames_total %d\n", dropped)
+	for id, count := range reconnects {
+		fmt.Fprintf(&sb, "engine_sink_reconnects_total{sink=%q} %d\n", id, count)
+	}
+	return sb.String()
+}
+
+func percentileIndex(n int, p float64) int {
+	idx := int(float64(n)*p) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= n {
+		idx = n - 1
+	}
+	return idx
+}
@@ -0,0 +1,25 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/fogleman/gg"
+)
+
+// Input is a single command destined for the active Scene: a chat command
+// routed through chat.Router, an HTTP-triggered action, or a raw console
+// line — main.go decides how to turn those into Inputs.
+type Input struct {
+	Command string
+	Args    []string
+}
+
+// Scene is a single game screen the Engine drives. Update advances
+// simulation by a fixed dt and consumes whatever Inputs arrived since the
+// last tick; Render draws the current state into ctx. Splitting the two
+// lets Update run at a steady simulation rate independent of how often
+// Render/streaming actually manage to keep up.
+type Scene interface {
+	Update(dt time.Duration, inputs []Input)
+	Render(ctx *gg.Context)
+}
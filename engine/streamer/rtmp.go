@@ -0,0 +1,377 @@
+package streamer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gen2brain/x264-go"
+	"github.com/nareix/joy4/av"
+	"github.com/nareix/joy4/codec/h264parser"
+	"github.com/nareix/joy4/format/flv"
+	"github.com/nareix/joy4/format/rtmp"
+)
+
+var (
+	ErrAbort = errors.New("abort")
+)
+
+const (
+	frameRate = 30
+	gopSize   = 60 // keyframe cadence, matching the old ffmpeg `-g 60`
+)
+
+// FileFrameStreamer implements FrameStreamer (and, as a no-op for now, the
+// audio.Streamer side of it) by encoding frames in-process with x264 and
+// pushing the resulting packets to an RTMP endpoint over a joy4 rtmp.Conn,
+// instead of shelling out to an ffmpeg process. Per-packet errors are
+// handled directly instead of guessing from a "broken pipe" string, and each
+// packet's timestamp is the wall-clock time since the current encoder was
+// created rather than whatever ffmpeg inferred from stdin timing.
+type FileFrameStreamer struct {
+	width, height int
+	streamURL     string
+
+	startedAt time.Time
+
+	mu               sync.Mutex
+	encoder          *x264.Encoder
+	codecData        av.VideoCodecData
+	conn             *rtmp.Conn
+	isAbort          bool
+	isReconnecting   bool
+	reconnectAttempt int
+
+	// HTTP-FLV preview: every subscriber gets its own flv.Muxer mirroring
+	// the same packets pushed to the RTMP connection, so /live.flv works
+	// without a second ffmpeg pull.
+	subMu       sync.Mutex
+	nextSubID   int
+	subscribers map[int]*flv.Muxer
+}
+
+// NewFileFrameStreamer creates the x264 encoder and connects to streamURL.
+func NewFileFrameStreamer(
+	outputDir, streamURL string,
+	width, height int,
+) (*FileFrameStreamer, error) {
+	s := &FileFrameStreamer{
+		width:       width,
+		height:      height,
+		streamURL:   streamURL,
+		subscribers: make(map[int]*flv.Muxer),
+	}
+
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// nalWriter is the io.Writer the x264 encoder streams its Annex-B output
+// into (per its real API, encoded bytes are pushed to a writer rather than
+// returned from Encode). It demuxes that byte stream into individual NAL
+// units, rebuilds the codec data from the SPS/PPS the encoder repeats ahead
+// of every keyframe, and hands each access unit's VCL NALs to onFrame as an
+// AVCC-style length-prefixed payload, the form joy4's av.Packet.Data expects.
+type nalWriter struct {
+	onFrame func(payload []byte, keyFrame bool)
+
+	codecData av.VideoCodecData
+	gotHeader bool
+}
+
+func (w *nalWriter) Write(p []byte) (int, error) {
+	nalus, _ := h264parser.SplitNALUs(p)
+
+	var sps, pps []byte
+	var payload bytes.Buffer
+	keyFrame := false
+
+	for _, nalu := range nalus {
+		if len(nalu) == 0 {
+			continue
+		}
+
+		switch nalu[0] & 0x1f {
+		case h264parser.NALU_SPS:
+			sps = nalu
+			continue
+		case h264parser.NALU_PPS:
+			pps = nalu
+			continue
+		}
+
+		if !h264parser.IsDataNALU(nalu) {
+			continue // drop AUD/SEI/etc, joy4 doesn't need them in the payload
+		}
+		if nalu[0]&0x1f == 5 {
+			keyFrame = true
+		}
+
+		var length [4]byte
+		binary.BigEndian.PutUint32(length[:], uint32(len(nalu)))
+		payload.Write(length[:])
+		payload.Write(nalu)
+	}
+
+	if sps != nil && pps != nil {
+		codecData, err := h264parser.NewCodecDataFromSPSAndPPS(sps, pps)
+		if err != nil {
+			return 0, fmt.Errorf("failed to build h264 codec data: %w", err)
+		}
+		w.codecData = codecData
+		w.gotHeader = true
+	}
+
+	if payload.Len() > 0 && w.onFrame != nil {
+		w.onFrame(payload.Bytes(), keyFrame)
+	}
+
+	return len(p), nil
+}
+
+// newEncoder creates a fresh x264 encoder tuned the same way the old ffmpeg
+// command line was (ultrafast/zerolatency, closed GOP of 60), swaps it into
+// s and closes whatever encoder it replaced so a long-running stream's
+// reconnects don't leak the C-side encoder state of every prior attempt.
+func (s *FileFrameStreamer) newEncoder() error {
+	nw := &nalWriter{}
+	nw.onFrame = func(payload []byte, keyFrame bool) {
+		s.writeVideoPacket(av.Packet{
+			IsKeyFrame: keyFrame,
+			Time:       time.Since(s.startedAt),
+			Data:       payload,
+		})
+	}
+
+	encoder, err := x264.NewEncoder(nw, &x264.Options{
+		Width:       s.width,
+		Height:      s.height,
+		FrameRate:   frameRate,
+		RateControl: "abr",
+		Bitrate:     3000,
+		KeyInt:      gopSize,
+		Tune:        "zerolatency",
+		Preset:      "ultrafast",
+		Profile:     "high",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create x264 encoder: %w", err)
+	}
+	if !nw.gotHeader {
+		_ = encoder.Close()
+		return fmt.Errorf("x264 encoder did not emit SPS/PPS headers")
+	}
+
+	s.mu.Lock()
+	oldEncoder := s.encoder
+	s.encoder = encoder
+	s.codecData = nw.codecData
+	s.mu.Unlock()
+
+	if oldEncoder != nil {
+		_ = oldEncoder.Close()
+	}
+
+	s.startedAt = time.Now()
+	return nil
+}
+
+// connect (re)creates the encoder and dials the RTMP endpoint, writing the
+// stream header with the fresh SPS/PPS before any frame is streamed.
+func (s *FileFrameStreamer) connect() error {
+	if err := s.newEncoder(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	codecData := s.codecData
+	s.mu.Unlock()
+
+	conn, err := rtmp.Dial(s.streamURL)
+	if err != nil {
+		return fmt.Errorf("failed to dial rtmp endpoint: %w", err)
+	}
+	if err := conn.WriteHeader([]av.CodecData{codecData}); err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("failed to write stream header: %w", err)
+	}
+
+	s.mu.Lock()
+	s.conn = conn
+	s.isReconnecting = false
+	s.reconnectAttempt = 0
+	s.mu.Unlock()
+
+	return nil
+}
+
+// reconnect tears down the current RTMP connection and reconnects with
+// exponential backoff, replacing the old errorBucket heuristic. It gives up
+// and sets isAbort once the backoff would exceed 5 minutes.
+func (s *FileFrameStreamer) reconnect() error {
+	s.mu.Lock()
+	if s.isReconnecting {
+		s.mu.Unlock()
+		return fmt.Errorf("already reconnecting")
+	}
+	s.isReconnecting = true
+	s.mu.Unlock()
+
+	for {
+		s.mu.Lock()
+		s.reconnectAttempt++
+		attempt := s.reconnectAttempt
+		s.mu.Unlock()
+
+		backoff := time.Duration(1<<uint(attempt-1)) * time.Second
+		if backoff > 5*time.Minute {
+			s.mu.Lock()
+			s.isAbort = true
+			s.mu.Unlock()
+			return ErrAbort
+		}
+
+		fmt.Println("rtmp connection lost, reconnecting in", backoff, "(attempt", attempt, ")")
+		time.Sleep(backoff)
+
+		if err := s.connect(); err != nil {
+			fmt.Printf("failed to reconnect: %v\n", err)
+			continue
+		}
+
+		fmt.Println("rtmp connection reestablished")
+		return nil
+	}
+}
+
+// SubscribeFLV registers w to receive the same muxed byte stream this sink
+// pushes over RTMP, so an HTTP handler can serve /live.flv without a second
+// ffmpeg pull. It writes the FLV header with the current codec data
+// immediately so a client that connects mid-stream gets a valid file.
+func (s *FileFrameStreamer) SubscribeFLV(w io.Writer) (id int, err error) {
+	s.mu.Lock()
+	codecData := s.codecData
+	s.mu.Unlock()
+
+	muxer := flv.NewMuxer(w)
+	if err := muxer.WriteHeader([]av.CodecData{codecData}); err != nil {
+		return 0, fmt.Errorf("failed to write flv header: %w", err)
+	}
+
+	s.subMu.Lock()
+	id = s.nextSubID
+	s.nextSubID++
+	s.subscribers[id] = muxer
+	s.subMu.Unlock()
+
+	return id, nil
+}
+
+// UnsubscribeFLV stops writing to the subscriber registered under id, e.g.
+// once its HTTP connection closes.
+func (s *FileFrameStreamer) UnsubscribeFLV(id int) {
+	s.subMu.Lock()
+	delete(s.subscribers, id)
+	s.subMu.Unlock()
+}
+
+// mirrorToSubscribers writes pkt to every HTTP-FLV subscriber, dropping any
+// whose connection has broken.
+func (s *FileFrameStreamer) mirrorToSubscribers(pkt av.Packet) {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+
+	for id, muxer := range s.subscribers {
+		if err := muxer.WritePacket(pkt); err != nil {
+			delete(s.subscribers, id)
+		}
+	}
+}
+
+// writeVideoPacket pushes pkt to the current RTMP connection and mirrors it
+// to HTTP-FLV subscribers on success, kicking off a reconnect on failure.
+// It's called synchronously from the nalWriter's onFrame callback, i.e.
+// from inside encoder.Encode itself, so it reaches the connection through
+// the same lock Stream and Close use.
+func (s *FileFrameStreamer) writeVideoPacket(pkt av.Packet) {
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+
+	if conn == nil {
+		return
+	}
+
+	if err := conn.WritePacket(pkt); err != nil {
+		go func() {
+			if reconnectErr := s.reconnect(); reconnectErr != nil {
+				fmt.Printf("failed to reconnect: %v\n", reconnectErr)
+			}
+		}()
+		return
+	}
+	s.mirrorToSubscribers(pkt)
+}
+
+// Stream encodes frame with x264, which pushes the resulting packet to the
+// RTMP connection itself via the encoder's writer (see nalWriter); x264's
+// own KeyInt cadence decides when a keyframe is due.
+func (s *FileFrameStreamer) Stream(frame image.Image) error {
+	s.mu.Lock()
+	abort := s.isAbort
+	reconnecting := s.isReconnecting
+	encoder := s.encoder
+	s.mu.Unlock()
+
+	if abort {
+		return ErrAbort
+	}
+	if reconnecting {
+		return nil
+	}
+
+	if err := encoder.Encode(frame); err != nil {
+		return fmt.Errorf("failed to encode frame: %w", err)
+	}
+	return nil
+}
+
+// StreamAudio implements audio.Streamer but intentionally does not mux
+// samples into the RTMP/FLV output yet: connect() and SubscribeFLV only
+// ever register a single (video) codec at stream index 0, and joy4's
+// WritePacket indexes into that list with no bounds check, so sending an
+// Idx:1 audio packet would panic on the very first call. Actually muxing
+// audio needs a real encoded codec (AAC/Speex/...) this package doesn't
+// have yet; until then this is a no-op so FileFrameStreamer still
+// satisfies audio.Streamer and the Mixer has somewhere to send samples.
+func (s *FileFrameStreamer) StreamAudio(samples []int16) error {
+	return nil
+}
+
+// Close closes the RTMP connection and the encoder.
+func (s *FileFrameStreamer) Close() error {
+	fmt.Println("closing the rtmp frame streamer")
+
+	s.mu.Lock()
+	conn := s.conn
+	encoder := s.encoder
+	s.mu.Unlock()
+
+	if conn != nil {
+		if err := conn.Close(); err != nil {
+			return err
+		}
+	}
+	if encoder != nil {
+		return encoder.Close()
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package streamer
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"net"
+	"sync"
+)
+
+// TextFrameStreamer renders each frame to ANSI-colored ASCII art and serves
+// it to any number of telnet-style TCP clients, for viewers without access
+// to the RTMP/FLV stream. Each client keeps its own copy of the last grid
+// it was sent so Stream only has to write the cells that actually changed.
+type TextFrameStreamer struct {
+	listener   net.Listener
+	cols, rows int
+
+	mu      sync.Mutex
+	clients map[net.Conn]*textClient
+}
+
+type textClient struct {
+	writer *bufio.Writer
+	lastFG []uint8 // last 256-color index written for each cell; nil until the first frame
+	primed bool
+}
+
+// NewTextFrameStreamer starts a TCP listener at addr and begins accepting
+// clients. cols/rows is the character grid size frames are downsampled to
+// (80x45 preserves 16:9).
+func NewTextFrameStreamer(addr string, cols, rows int) (*TextFrameStreamer, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &TextFrameStreamer{
+		listener: listener,
+		cols:     cols,
+		rows:     rows,
+		clients:  make(map[net.Conn]*textClient),
+	}
+
+	go s.acceptLoop()
+
+	return s, nil
+}
+
+func (s *TextFrameStreamer) acceptLoop() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.clients[conn] = &textClient{writer: bufio.NewWriter(conn)}
+		s.mu.Unlock()
+
+		go s.watchDisconnect(conn)
+	}
+}
+
+// watchDisconnect removes a client as soon as its connection goes away
+// (closed by the viewer, a Ctrl+C on their telnet, ...).
+func (s *TextFrameStreamer) watchDisconnect(conn net.Conn) {
+	buf := make([]byte, 1)
+	var prev byte
+	for {
+		_, err := conn.Read(buf)
+		// Ctrl+C arrives as a raw ETX byte, or from a line-mode telnet
+		// client as the IAC IP command (0xff 0xf4).
+		ctrlC := err == nil && (buf[0] == 0x03 || (prev == 0xff && buf[0] == 0xf4))
+		if err != nil || ctrlC {
+			s.mu.Lock()
+			delete(s.clients, conn)
+			s.mu.Unlock()
+			_ = conn.Close()
+			return
+		}
+		prev = buf[0]
+	}
+}
+
+// Stream downsamples frame to the character grid via nearest-neighbor,
+// quantizes each cell to the 256-color xterm palette, and pushes only the
+// cells that changed to every connected client.
+func (s *TextFrameStreamer) Stream(frame image.Image) error {
+	bounds := frame.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	grid := make([]uint8, s.cols*s.rows)
+	for row := 0; row < s.rows; row++ {
+		y := bounds.Min.Y + row*height/s.rows
+		for col := 0; col < s.cols; col++ {
+			x := bounds.Min.X + col*width/s.cols
+			r, g, b, _ := frame.At(x, y).RGBA()
+			grid[row*s.cols+col] = ansi256(uint8(r>>8), uint8(g>>8), uint8(b>>8))
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for conn, client := range s.clients {
+		if err := client.write(s.cols, s.rows, grid); err != nil {
+			delete(s.clients, conn)
+			_ = conn.Close()
+		}
+	}
+	return nil
+}
+
+// write emits \x1b[H followed by only the cells whose color changed since
+// the last frame this client was sent.
+func (c *textClient) write(cols, rows int, grid []uint8) error {
+	if _, err := c.writer.WriteString("\x1b[H"); err != nil {
+		return err
+	}
+
+	if !c.primed || len(c.lastFG) != len(grid) {
+		c.lastFG = make([]uint8, len(grid))
+		for i := range c.lastFG {
+			c.lastFG[i] = 255 // force every cell to redraw on the first frame
+		}
+		c.primed = true
+	}
+
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			idx := row*cols + col
+			if grid[idx] == c.lastFG[idx] {
+				continue
+			}
+			c.lastFG[idx] = grid[idx]
+			if _, err := fmt.Fprintf(c.writer, "\x1b[%d;%dH\x1b[48;5;%dm ", row+1, col+1, grid[idx]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return c.writer.Flush()
+}
+
+// ansi256 quantizes an RGB color to the nearest index in the standard
+// 256-color xterm palette: a 6x6x6 color cube (indices 16-231) plus a
+// 24-step grayscale ramp (indices 232-255).
+func ansi256(r, g, b uint8) uint8 {
+	toCube := func(c uint8) int {
+		return int(c) * 5 / 255
+	}
+	cr, cg, cb := toCube(r), toCube(g), toCube(b)
+
+	gray := (int(r) + int(g) + int(b)) / 3
+	if cr == cg && cg == cb {
+		// near-grayscale: prefer the finer 24-step grayscale ramp
+		if gray < 8 {
+			return 16 // pure black is already in the color cube
+		}
+		if gray > 248 {
+			return 231
+		}
+		return uint8(232 + (gray-8)*23/247)
+	}
+
+	return uint8(16 + 36*cr + 6*cg + cb)
+}
+
+// Close stops accepting new clients and disconnects everyone currently
+// connected.
+func (s *TextFrameStreamer) Close() error {
+	err := s.listener.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.clients {
+		_ = conn.Close()
+		delete(s.clients, conn)
+	}
+
+	return err
+}
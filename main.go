@@ -1,15 +1,18 @@
 package main
 
 import (
+	"YouTubeLiveGame/engine"
+	"YouTubeLiveGame/engine/audio"
+	"YouTubeLiveGame/engine/broadcast"
+	"YouTubeLiveGame/engine/chat"
 	"YouTubeLiveGame/engine/streamer"
 	"context"
 	_ "embed"
+	"encoding/binary"
 	"fmt"
-	"github.com/fogleman/gg"
 	"github.com/golang/freetype/truetype"
 	"github.com/labstack/echo/v4"
-	"google.golang.org/api/option"
-	"google.golang.org/api/youtube/v3"
+	"io"
 	"os"
 	"os/signal"
 	"strings"
@@ -25,189 +28,83 @@ var (
 //go:embed assets/font.ttf
 var fontBytes []byte
 
+// pcmToBytes packs interleaved int16 PCM samples into little-endian bytes
+// for local capture buffering.
+func pcmToBytes(samples []int16) []byte {
+	buf := make([]byte, len(samples)*2)
+	for i, sample := range samples {
+		binary.LittleEndian.PutUint16(buf[i*2:], uint16(sample))
+	}
+	return buf
+}
+
 func main() {
 	font, err := truetype.Parse(fontBytes)
 	if err != nil {
 		fmt.Println("couldn't parse font file", err)
 	}
+
 	//load connect to stream
-	fileFrameStreamer, err := streamer.NewFileFrameStreamer("./debug", os.Getenv("STREAM_URL"), width, height)
-	if err != nil {
-		panic(err)
+	broadcastManager := broadcast.NewManager("./debug", width, height)
+	if streamURL := os.Getenv("STREAM_URL"); streamURL != "" {
+		if err := broadcastManager.Start("default", streamURL); err != nil {
+			panic(err)
+		}
+	}
+	if telnetAddr := os.Getenv("TELNET_ADDR"); telnetAddr != "" {
+		textFrameStreamer, err := streamer.NewTextFrameStreamer(telnetAddr, 80, 45)
+		if err != nil {
+			panic(err)
+		}
+		broadcastManager.Register("telnet", textFrameStreamer)
+		fmt.Println("> telnet viewers can connect at", telnetAddr)
 	}
 
 	fmt.Println("> connected to the streaming service")
 	appCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT)
 	defer stop()
 
-	input := make(chan string, 100)
-
-	//statistics
-	frameCounts := 0
-	frameDurations := time.Duration(0)
-	errorsCount := 0
-	titleText := "YouTube Live Based Game Engine!"
-	isPaused := false
-	avgFrameTime := func() time.Duration {
-		if frameCounts == 0 {
-			return frameDurations
-		}
-		return frameDurations / time.Duration(frameCounts)
-	}
-	lastBangTime := time.Now().Add(-time.Hour)
-	// frame generation
-	frameNum := 0
-	go func() {
-		frame := gg.NewContext(width, height)
-		// load assets
-		largeFont := truetype.NewFace(font, &truetype.Options{Size: 30})
-		smallFont := truetype.NewFace(font, &truetype.Options{Size: 12})
-		// run
-		ticker := time.NewTicker(time.Second / 30)
-
-		// cached
-
-		for {
-			<-ticker.C
-
-			frameNum++
-			// frame statistics
-			if frameNum%100 == 0 {
-				errorsCount = 0
-				frameDurations = avgFrameTime()
-				frameCounts = 1
-			}
-
-			// the app logic
-			color := float64(frameNum%200) / 1000
-			if time.Since(lastBangTime) < time.Second*5 {
-				color = float64(time.Since(lastBangTime)) / float64(time.Second) * 5
-			}
+	mixer := audio.NewMixer()
+	scene := newGameScene(width, height, font, mixer)
 
-			startedTime := time.Now()
-			frame.SetRGB(color, color, color)
-			frame.Clear()
+	eng := engine.NewEngine(width, height, scene, broadcastManager)
+	eng.AudioSource = scene.MixAudio
 
-			frame.SetRGB(1, 1, 1)
-			frame.SetFontFace(largeFont)
-			frame.DrawStringWrapped(titleText, 50, 100, 0, 0, float64(width)-50, 1.5, gg.AlignLeft)
+	engineDone := make(chan struct{})
+	go eng.Run(engineDone)
 
-			frame.SetFontFace(smallFont)
-			frame.DrawString(fmt.Sprintf("frame: %d", frameNum), 10, 22)
-			if frameCounts > 0 {
-				frame.DrawString(fmt.Sprintf("avg frame time: %s", avgFrameTime().String()), 10, 42)
-			}
-
-			frame.DrawCircle(float64(frameNum*2%width), 600, 50)
-			frame.Fill()
-
-			if !isPaused {
-				// save and publish
-				if err := fileFrameStreamer.Stream(frame.Image()); err != nil {
-					fmt.Println("error happened", err)
-					errorsCount++
-					if errorsCount > 5 {
-						fmt.Println("too many errors!")
-						stop()
-						return
-					}
-				}
-			}
-			frameDurations += time.Since(startedTime)
-			frameCounts += 1
-
-			// handle inputs
-
-			select {
-			case line := <-input:
-				args := strings.Fields(line)
-				if len(args) == 0 {
-					continue
-				}
-				fmt.Println("\n\nResult for ", args[0])
-				if args[0] == "info" {
-					fmt.Println("#", frameNum)
-					fmt.Println("average frame duration:", frameDurations/time.Duration(frameCounts))
-					fmt.Println("average error count:", errorsCount)
-				}
-
-				if args[0] == "capture" {
-					if err := frame.SavePNG("./debug/capture.png"); err != nil {
-						fmt.Println("error happened", err)
-					} else {
-						fmt.Println("captured successfully")
-					}
-				}
-
-				if args[0] == "setText" {
-					if len(args) == 1 {
-						continue
-					}
-					titleText = strings.Join(args[1:], " ")
-				}
-
-				if args[0] == "toggle" {
-					isPaused = !isPaused
-				}
-
-				if args[0] == "bang" {
-					lastBangTime = time.Now()
-				}
-
-			default:
-			}
-		}
-	}()
+	// chat routing: any connected chat.Source feeds through the same
+	// router, which turns recognized commands into Inputs for the engine.
+	router := chat.NewRouter(500 * time.Millisecond)
+	router.On("bang", func(msg chat.Message) {
+		eng.Enqueue(engine.Input{Command: "bang"})
+	})
 
 	chatIdChannel := make(chan string)
 	go func() {
 		chatId := <-chatIdChannel
 		fmt.Println("received chat id", chatId)
-		service, err := youtube.NewService(context.Background(), option.WithAPIKey(os.Getenv("YOUTUBE_LIVE_API")))
+		source, err := chat.NewYouTubeSource(os.Getenv("YOUTUBE_LIVE_API"), chatId)
 		if err != nil {
 			panic(err)
 		}
-
-		errCount := 0
-		for {
-			if errCount > 10 {
-				fmt.Println("too many errors!")
-				errCount = 0
-				<-time.After(time.Minute)
-			}
-			if err := service.LiveChatMessages.List(chatId, []string{"snippet", "authorDetails"}).Pages(context.Background(), func(response *youtube.LiveChatMessageListResponse) error {
-				for _, item := range response.Items {
-					message := strings.ToLower(item.Snippet.TextMessageDetails.MessageText)
-					if message == "bang" {
-						select {
-						case input <- "bang":
-						default:
-						}
-					}
-				}
-				<-time.After(time.Second * 3)
-				return nil
-			}); err != nil {
-				errCount++
-				fmt.Println("error happened ", err, " count ", errCount, " waiting for 10 seconds")
-				<-time.After(time.Second * 10)
-			}
-		}
+		router.Run(source)
 	}()
 
 	go func() {
 		e := echo.New()
 		e.GET("/info", func(c echo.Context) error {
+			eng.Enqueue(engine.Input{Command: "info"})
 			return c.JSON(200, map[string]interface{}{
-				"ok":                 true,
-				"frame":              frameNum,
-				"avg_frame_duration": avgFrameTime().String(),
+				"ok":      true,
+				"frame":   scene.frameNum,
+				"metrics": eng.Metrics().Render(),
 			})
 		})
 
 		e.GET("/setText", func(c echo.Context) error {
 			txt := c.QueryParam("text")
-			input <- "setText " + txt
+			eng.Enqueue(engine.Input{Command: "setText", Args: strings.Fields(txt)})
 			return c.JSON(200, map[string]interface{}{
 				"ok": true,
 			})
@@ -222,12 +119,88 @@ func main() {
 		})
 
 		e.GET("/bang", func(c echo.Context) error {
-			input <- "bang"
+			eng.Enqueue(engine.Input{Command: "bang"})
 			return c.JSON(200, map[string]interface{}{
 				"ok": true,
 			})
 		})
 
+		e.GET("/broadcast/add", func(c echo.Context) error {
+			id := c.QueryParam("id")
+			url := c.QueryParam("url")
+			if id == "" || url == "" {
+				return c.JSON(400, map[string]interface{}{
+					"ok":    false,
+					"error": "id and url are required",
+				})
+			}
+			if err := broadcastManager.Start(id, url); err != nil {
+				return c.JSON(500, map[string]interface{}{
+					"ok":    false,
+					"error": err.Error(),
+				})
+			}
+			return c.JSON(200, map[string]interface{}{
+				"ok": true,
+			})
+		})
+
+		e.GET("/broadcast/remove", func(c echo.Context) error {
+			id := c.QueryParam("id")
+			if err := broadcastManager.Stop(id); err != nil {
+				return c.JSON(400, map[string]interface{}{
+					"ok":    false,
+					"error": err.Error(),
+				})
+			}
+			return c.JSON(200, map[string]interface{}{
+				"ok": true,
+			})
+		})
+
+		e.GET("/broadcast/list", func(c echo.Context) error {
+			return c.JSON(200, map[string]interface{}{
+				"ok":    true,
+				"sinks": broadcastManager.List(),
+			})
+		})
+
+		e.GET("/live.flv", func(c echo.Context) error {
+			sink, ok := broadcastManager.Get("default")
+			if !ok {
+				return c.JSON(404, map[string]interface{}{
+					"ok":    false,
+					"error": "no default sink",
+				})
+			}
+			flvSink, ok := sink.(interface {
+				SubscribeFLV(w io.Writer) (int, error)
+				UnsubscribeFLV(id int)
+			})
+			if !ok {
+				return c.JSON(400, map[string]interface{}{
+					"ok":    false,
+					"error": "default sink doesn't support HTTP-FLV preview",
+				})
+			}
+
+			c.Response().Header().Set(echo.HeaderContentType, "video/x-flv")
+			c.Response().WriteHeader(200)
+
+			id, err := flvSink.SubscribeFLV(c.Response())
+			if err != nil {
+				return err
+			}
+			defer flvSink.UnsubscribeFLV(id)
+
+			<-c.Request().Context().Done()
+			return nil
+		})
+
+		e.GET("/metrics", func(c echo.Context) error {
+			return c.String(200, eng.Metrics().Render())
+		})
+
 		port := os.Getenv("PORT")
 		if port == "" {
 			port = ":8081"
@@ -237,7 +210,8 @@ func main() {
 
 	<-appCtx.Done()
 	fmt.Println("> closing application")
-	if err := fileFrameStreamer.Close(); err != nil {
+	close(engineDone)
+	if err := broadcastManager.Close(); err != nil {
 		panic(err)
 	}
 }
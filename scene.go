@@ -0,0 +1,148 @@
+package main
+
+import (
+	"YouTubeLiveGame/engine"
+	"YouTubeLiveGame/engine/audio"
+	"bytes"
+	"fmt"
+	"image"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fogleman/gg"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+)
+
+// captureAudioWindow bounds how much mixed PCM gameScene keeps around for
+// the `capture` command, so a long-running stream doesn't grow this buffer
+// forever.
+const captureAudioWindow = 5 * time.Second
+
+// gameScene is the game engine's only Scene today: the same title-card +
+// bang-flash + circle demo main.go used to draw directly in its ticker
+// loop, now split into Update (simulation, driven at engine.SimulationHz)
+// and Render (drawing, driven at engine.RenderHz).
+type gameScene struct {
+	width, height int
+	largeFont     font.Face
+	smallFont     font.Face
+
+	titleText      string
+	isPaused       bool
+	lastBangTime   time.Time
+	pendingCapture bool
+	frameNum       int
+
+	mixer                *audio.Mixer
+	captureAudio         *bytes.Buffer
+	maxCaptureAudioBytes int
+}
+
+func newGameScene(width, height int, font *truetype.Font, mixer *audio.Mixer) *gameScene {
+	return &gameScene{
+		width:        width,
+		height:       height,
+		largeFont:    truetype.NewFace(font, &truetype.Options{Size: 30}),
+		smallFont:    truetype.NewFace(font, &truetype.Options{Size: 12}),
+		titleText:    "YouTube Live Based Game Engine!",
+		lastBangTime: time.Now().Add(-time.Hour),
+		mixer:        mixer,
+		captureAudio: &bytes.Buffer{},
+		maxCaptureAudioBytes: int(captureAudioWindow.Seconds() *
+			float64(audio.SampleRate*audio.Channels*2)),
+	}
+}
+
+// Update implements engine.Scene, applying whatever commands arrived since
+// the last tick.
+func (g *gameScene) Update(dt time.Duration, inputs []engine.Input) {
+	for _, in := range inputs {
+		switch in.Command {
+		case "setText":
+			if len(in.Args) > 0 {
+				g.titleText = strings.Join(in.Args, " ")
+			}
+		case "toggle":
+			g.isPaused = !g.isPaused
+		case "bang":
+			g.lastBangTime = time.Now()
+		case "capture":
+			g.pendingCapture = true
+		case "info":
+			fmt.Println("#", g.frameNum)
+		}
+	}
+}
+
+// Render implements engine.Scene, drawing the title card, the bang-flash
+// background, and a moving circle, then saving a capture snapshot if one
+// was requested via Update.
+func (g *gameScene) Render(ctx *gg.Context) {
+	g.frameNum++
+
+	color := float64(g.frameNum%200) / 1000
+	if time.Since(g.lastBangTime) < time.Second*5 {
+		color = float64(time.Since(g.lastBangTime)) / float64(time.Second) * 5
+	}
+
+	ctx.SetRGB(color, color, color)
+	ctx.Clear()
+
+	ctx.SetRGB(1, 1, 1)
+	ctx.SetFontFace(g.largeFont)
+	ctx.DrawStringWrapped(g.titleText, 50, 100, 0, 0, float64(g.width)-50, 1.5, gg.AlignLeft)
+
+	ctx.SetFontFace(g.smallFont)
+	ctx.DrawString(fmt.Sprintf("frame: %d", g.frameNum), 10, 22)
+
+	ctx.DrawCircle(float64(g.frameNum*2%g.width), 600, 50)
+	ctx.Fill()
+
+	if g.pendingCapture {
+		g.pendingCapture = false
+		if err := g.saveCapture(ctx.Image()); err != nil {
+			fmt.Println("error happened", err)
+		} else {
+			fmt.Println("captured successfully")
+		}
+	}
+}
+
+// Paused implements the engine's optional pausable interface: when paused,
+// the engine keeps rendering and mixing locally but stops pushing frames to
+// the broadcast sinks, matching the old main.go's `if !isPaused` guard.
+func (g *gameScene) Paused() bool {
+	return g.isPaused
+}
+
+// MixAudio pulls one render tick's worth of mixed PCM out of the mixer and
+// buffers it for the next capture, so it can be wired up as an
+// engine.Engine.AudioSource.
+func (g *gameScene) MixAudio() []int16 {
+	samplesPerFrame := audio.SampleRate / engine.RenderHz * audio.Channels
+	mixed := g.mixer.Mix(samplesPerFrame)
+
+	g.captureAudio.Write(pcmToBytes(mixed))
+	if g.captureAudio.Len() > g.maxCaptureAudioBytes {
+		g.captureAudio.Next(g.captureAudio.Len() - g.maxCaptureAudioBytes)
+	}
+
+	return mixed
+}
+
+// saveCapture dumps the current frame and the buffered capture audio to
+// ./debug, giving the `capture` command synchronized video+audio.
+func (g *gameScene) saveCapture(frame image.Image) error {
+	if err := gg.SavePNG("./debug/capture.png", frame); err != nil {
+		return err
+	}
+
+	mp3, err := audio.PcmToMp3(bytes.NewBuffer(g.captureAudio.Bytes()))
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile("./debug/capture.mp3", mp3.Bytes(), 0644)
+}